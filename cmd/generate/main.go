@@ -0,0 +1,217 @@
+// Command generate is the asset compiler: it scans a source directory
+// (typically "files", the same tree main.go serves under /files/ and reads
+// templates from) and writes generate/generated_files.go and
+// generate/generated_manifest.go, the two "DO NOT EDIT" files that bake
+// those assets into the executable.
+//
+// Every file under srcDir is embedded verbatim in generated_files.go.
+// Every file NOT under a "templates/" directory also gets an entry in
+// generated_manifest.go: a cache-busting URL carrying a content hash, an
+// ETag, and - for compressible content types - a gzip-precompressed copy.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	srcDir := flag.String("src", "files", "directory to scan for templates and assets")
+	outDir := flag.String("out", "generate", "directory to write the generated Go source to")
+	flag.Parse()
+
+	files, err := scan(*srcDir)
+	if err != nil {
+		log.Fatalf("scanning %s: %v", *srcDir, err)
+	}
+
+	if err := writeGeneratedFiles(*outDir, files); err != nil {
+		log.Fatalf("writing generated_files.go: %v", err)
+	}
+	if err := writeGeneratedManifest(*outDir, files); err != nil {
+		log.Fatalf("writing generated_manifest.go: %v", err)
+	}
+}
+
+// scan reads every regular file under srcDir, returning its contents keyed
+// by its path relative to the repository root (e.g. "files/app.css").
+func scan(srcDir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.Walk(srcDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		contents, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(p)] = contents
+		return nil
+	})
+	return files, err
+}
+
+// isTemplate reports whether path is under a "templates" directory -
+// templates are embedded but not published as cache-busted assets.
+func isTemplate(path string) bool {
+	for _, part := range strings.Split(path, "/") {
+		if part == "templates" {
+			return true
+		}
+	}
+	return false
+}
+
+// compressibleContentType reports whether content of this type is worth
+// gzip-precompressing.
+func compressibleContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "javascript") || strings.Contains(contentType, "json")
+}
+
+// contentTypeByExt pins the Content-Type served for each asset extension,
+// rather than relying on mime.TypeByExtension, whose answer (e.g.
+// "text/javascript" vs "application/javascript" for ".js") depends on the
+// host's mime database and isn't guaranteed to match what was committed by
+// a previous run of this tool on a different machine.
+var contentTypeByExt = map[string]string{
+	".html": "text/html; charset=utf-8",
+	".css":  "text/css; charset=utf-8",
+	".js":   "application/javascript; charset=utf-8",
+}
+
+func contentTypeFor(ext string) string {
+	if ct, ok := contentTypeByExt[ext]; ok {
+		return ct
+	}
+	if ct := mime.TypeByExtension(ext); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// goStringLiteral renders s as a backtick raw string literal, matching the
+// style of the hand-authored files this tool replaces, unless s contains a
+// backtick (which would end the literal) or a carriage return (which Go
+// discards from raw string literals), in which case it falls back to a
+// standard quoted string literal.
+func goStringLiteral(s string) string {
+	if !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+func writeGeneratedFiles(outDir string, files map[string][]byte) error {
+	var paths []string
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var b bytes.Buffer
+	fmt.Fprint(&b, `// Code generated by the godesktopgui asset compiler from the contents of
+// the "files" directory. DO NOT EDIT.
+//
+// See newCompiledFileSystem in filesystem.go for how CompiledFileSystem is
+// built from this map.
+package generate
+
+var compiledFiles = map[string]string{
+`)
+	for _, p := range paths {
+		fmt.Fprintf(&b, "\t%q: %s,\n", p, goStringLiteral(string(files[p])))
+	}
+	fmt.Fprint(&b, `}
+
+// CompiledFileSystem is the AssetSource that presents the contents of
+// compiledFiles (and therefore the original "files" directory) as if they
+// were ordinary files on disk, without any run-time dependency on disk
+// access.
+var CompiledFileSystem = NewGeneratedAssetSource(compiledFiles)
+`)
+	return writeFormatted(filepath.Join(outDir, "generated_files.go"), b.Bytes())
+}
+
+func writeGeneratedManifest(outDir string, files map[string][]byte) error {
+	var names []string
+	for p := range files {
+		if !isTemplate(p) {
+			names = append(names, p)
+		}
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprint(&b, `// Code generated by the godesktopgui asset compiler from the contents of
+// the "files" directory. DO NOT EDIT.
+//
+// See cmd/generate for the tool that produces this file, and
+// generated_files.go for the asset contents it describes.
+package generate
+
+// GeneratedManifest is the AssetManifest for the assets in compiledFiles,
+// keyed by logical name.
+var GeneratedManifest = NewAssetManifest(map[string]AssetMeta{
+`)
+	for _, p := range names {
+		content := files[p]
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])
+		name := filepath.Base(p)
+		ext := filepath.Ext(name)
+		hashedName := strings.TrimSuffix(name, ext) + "." + hash[:12] + ext
+		contentType := contentTypeFor(ext)
+
+		fmt.Fprintf(&b, "\t%q: {\n", name)
+		fmt.Fprintf(&b, "\t\tURL:         %q,\n", "/files/"+hashedName)
+		fmt.Fprintf(&b, "\t\tRealPath:    %q,\n", p)
+		fmt.Fprintf(&b, "\t\tContentType: %q,\n", contentType)
+		fmt.Fprintf(&b, "\t\tETag:        `\"%s\"`,\n", hash)
+		if compressibleContentType(contentType) {
+			gz, err := gzipCompress(content)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&b, "\t\tGzipContent: mustDecodeBase64(%q),\n", base64.StdEncoding.EncodeToString(gz))
+		}
+		fmt.Fprint(&b, "\t},\n")
+	}
+	fmt.Fprint(&b, "})\n")
+	return writeFormatted(filepath.Join(outDir, "generated_manifest.go"), b.Bytes())
+}
+
+func gzipCompress(content []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func writeFormatted(path string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("gofmt: %w", err)
+	}
+	return ioutil.WriteFile(path, formatted, 0644)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedFilesAreUpToDate runs the generator exactly as documented
+// (`go run ./cmd/generate -src files` from the repo root) and diffs its
+// output against the committed generate/generated_files.go and
+// generate/generated_manifest.go, so the "Code generated ... DO NOT EDIT"
+// banner they carry stays honest: if this fails, those files are stale and
+// need regenerating. The GzipContent payloads are compared byte for byte,
+// and compress/gzip's output can differ between Go releases, so a
+// different toolchain can also make this fail for unchanged files/.
+func TestGeneratedFilesAreUpToDate(t *testing.T) {
+	repoRoot, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	files, err := scan("files")
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	outDir, err := ioutil.TempDir("", "generate-regen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	if err := writeGeneratedFiles(outDir, files); err != nil {
+		t.Fatalf("writeGeneratedFiles: %v", err)
+	}
+	if err := writeGeneratedManifest(outDir, files); err != nil {
+		t.Fatalf("writeGeneratedManifest: %v", err)
+	}
+
+	for _, name := range []string{"generated_files.go", "generated_manifest.go"} {
+		got, err := ioutil.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ioutil.ReadFile(filepath.Join(repoRoot, "generate", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("generate/%s is stale: regenerating from files/ does not reproduce it", name)
+		}
+	}
+}
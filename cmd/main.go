@@ -4,129 +4,176 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"html/template"
-	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/peterhoward42/godesktopgui/generate"
+	"github.com/peterhoward42/godesktopgui/guidata"
+	"github.com/peterhoward42/godesktopgui/snapshot"
 	"github.com/pkg/browser"
 )
 
-// htmlTemplate generates the HTML we serve to implement the GUI when we call
-// its ExecuteTemplate method.
-var htmlTemplate *template.Template
+// templateEngine renders the GUI's pages, combining them with their master
+// layout and shared partials.
+var templateEngine *generate.TemplateEngine
 
-func main() {
+// store holds the GUI's live data model, shared between the initial page
+// render, the SSE event stream and the WebSocket action handler.
+var store = guidata.NewStore(populateGuiData())
+
+// stopServerChan is signalled (by the /quit endpoint, or by an incoming
+// SIGINT/SIGTERM) to ask the server to shut down.
+var stopServerChan = make(chan bool)
 
-	// Prepare the html template that will be combined with a data model to
-	// serve html pages.
+// templateFuncs are the helpers made available to every template the
+// engine renders.
+var templateFuncs = template.FuncMap{
+	"formatTime": func(t time.Time) string { return t.Format("15:04:05 MST") },
+	"safeHTML":   func(s string) template.HTML { return template.HTML(s) },
+	"asset":      generate.GeneratedManifest.URL,
+}
 
-	htmlTemplate = parseTemplate()
+func main() {
+	devMode := flag.Bool("dev", false, "re-read templates from the files/ directory on disk instead of the compiled-in copy")
+	snapshotDir := flag.String("snapshot", "", "write a fully-rendered static copy of the GUI to this directory, instead of launching it")
+	flag.Parse()
+
+	// Prepare the template engine that will combine the GUI's pages with
+	// their layout and partials. assetSource is declared with the explicit
+	// AssetSource interface type (not :=) so that switching it to
+	// NewDevAssetSource below can never fail to type-check against whatever
+	// concrete type generate.CompiledFileSystem happens to be.
+	var assetSource generate.AssetSource = generate.CompiledFileSystem
+	if *devMode {
+		assetSource = generate.NewDevAssetSource(".")
+	}
+	var err error
+	templateEngine, err = generate.NewTemplateEngine(assetSource, "files/templates", templateFuncs, *devMode)
+	if err != nil {
+		log.Fatalf("generate.NewTemplateEngine: %v", err)
+	}
 
-	// The html we serve has href links to css and .js files - the URLs of which
-	// start with /files, so we route all /files requests to the standard
-	// library http.FileServer. The FileServer requires that we provide
-	// an http.FileSystem. And that is how the compiled-in files present
-	// themselves. See the generate package for how this gets created.
+	mux := http.NewServeMux()
 
-	http.Handle("/files/", http.FileServer(generate.CompiledFileSystem))
+	// The html we serve has href links to css and .js files, resolved via
+	// the "asset" template func to the hashed URLs in generate.GeneratedManifest.
+	// Serving them through generate.NewAssetHandler (rather than a plain
+	// http.FileServer) gets us ETag, long-lived Cache-Control and gzip
+	// support for free.
+	mux.Handle("/files/", generate.NewAssetHandler(assetSource, generate.GeneratedManifest, *devMode))
 
 	// The GUI home page has its own dedicated handler.
-	http.HandleFunc("/thegui", guiHandler)
+	mux.HandleFunc("/thegui", guiHandler)
+
+	// The GUI's data model is also available as an SSE stream and a
+	// WebSocket of client actions, so the rendered page can stay live
+	// without the user ever reloading it.
+	mux.HandleFunc("/thegui/events", store.ServeSSE)
+	mux.HandleFunc("/thegui/ws", store.ServeWS)
+
+	// A "quit" endpoint lets the served page ask us to shut down, so this
+	// behaves like a desktop app that exits when the user closes its window.
+	mux.HandleFunc("/quit", quitHandler)
+
+	// A -snapshot flag renders a static copy of the GUI to disk instead of
+	// launching it, by driving these same handlers in-process.
+	if *snapshotDir != "" {
+		if err := snapshot.Generate(mux, assetSource, generate.GeneratedManifest, []string{"/thegui"}, *snapshotDir); err != nil {
+			log.Fatalf("snapshot.Generate: %v", err)
+		}
+		return
+	}
+
+	// Bind to an ephemeral port on loopback, so we never collide with
+	// another instance of this app, or anything else already listening
+	// on a fixed port.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("net.Listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	server := &http.Server{Handler: mux}
 
 	// Spin-up the standard library's http server in a separate goroutine.
 	go func() {
-		err := http.ListenAndServe(":8080", nil)
-		if err != nil {
-			log.Fatalf("http.ListenAndServe: %v", err)
+		err := server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server.Serve: %v", err)
 		}
 	}()
 
-	// Give the server time to be ready.
-	time.Sleep(3 * time.Second)
-
-	// Then bring up a browser window or tab pointing to it.
+	// Bring up a browser window or tab pointing to the port we were
+	// actually given.
 	// Note this is asynchronous, and the call returns immediately.
-	err := browser.OpenURL("http://127.0.0.1:8080/thegui")
+	url := fmt.Sprintf("http://127.0.0.1:%d/thegui", port)
+	err = browser.OpenURL(url)
 	if err != nil {
 		log.Fatalf("browser.Open: %v", err)
 	}
 
-	// Keep the main goroutine alive.
-	wait := make(chan bool)
-	<-wait
+	waitForShutdownSignal()
 
-	log.Printf("Finished normally")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("server.Shutdown: %v", err)
+	}
 
+	log.Printf("Finished normally")
 }
 
-// parseTemplate retreives a template HTML file from the compiled-in
-// file system, and parses it using the standard library Template.Parse
-// to create a Template object.
-func parseTemplate() *template.Template {
-	fName := "files/templates/maingui.html"
-	file, err := generate.CompiledFileSystem.Open(fName)
-	if err != nil {
-		log.Fatalf("Failed to open <%s>: %v", fName, err)
-	}
-	defer file.Close()
-	contents, err := ioutil.ReadAll(file)
-	if err != nil {
-		log.Fatalf("Failed to read contents of file: %v", err)
-	}
-	t, err := template.New("gui").Parse(string(contents))
-	if err != nil {
-		log.Fatalf("Failed to parse template: %v", err)
+// waitForShutdownSignal blocks until either the /quit handler signals
+// stopServerChan, or the process receives SIGINT/SIGTERM.
+func waitForShutdownSignal() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-stopServerChan:
+	case <-sigChan:
 	}
-	return t
-}
-
-// GuiData holds the GUI state data that will be combined with the
-// template to render the GUI HTML. Note it is a hierarchical structure -
-// having a slice of TableRow - which the directives in the templating
-// system is clever enough to iterate over.
-type GuiData struct {
-	Title       string
-	Unwatch     int
-	Star        int
-	Fork        int
-	Commits     int
-	Branch      int
-	Release     int
-	Contributor int
-	RowsInTable []TableRow
 }
 
-// TableRow is a slave model to describe a single row in an HTML table.
-type TableRow struct {
-	File    string
-	Comment string
-	Ago     string
-	Icon    string
+// quitHandler lets the GUI ask the server to shut down cleanly, which is
+// how this app exits when the user closes its window.
+func quitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	go func() {
+		stopServerChan <- true
+	}()
 }
 
-// guiHandler serves the GUI. Simple as that.
+// guiHandler serves the GUI, rendered from the store's current data. The
+// page then keeps itself up to date by subscribing to /thegui/events.
 func guiHandler(w http.ResponseWriter, r *http.Request) {
+	dynamicData := store.Current()
 
-	// Set the stateful parameters of the Gui data model - to create the
-	// the dynamic user experience.
-	dynamicData := populateGuiData()
-
-	// This (standard library) call combines the template with the data model
-	// to produce the required HTML. What is not obvious is that it does not
-	// return the HTML here, but is capable, in of itself, of writing the HTML
-	// it generates directly to the http.ResponseWriter provided.
-	err := htmlTemplate.ExecuteTemplate(w, "gui", dynamicData)
+	// This combines the "maingui" page with its master layout and partials
+	// to produce the required HTML, writing it directly to the
+	// http.ResponseWriter provided.
+	err := templateEngine.Render(w, "maingui", dynamicData)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
-// populateGuiData populates a GuiData trivially with hard-coded data.
-func populateGuiData() *GuiData {
-	guiData := &GuiData{
+// populateGuiData populates a guidata.GuiData trivially with hard-coded
+// data, to seed the store's initial value.
+func populateGuiData() guidata.GuiData {
+	data := guidata.GuiData{
 		Title:       "Golang Standalone GUI Example",
 		Unwatch:     3,
 		Star:        0,
@@ -134,17 +181,18 @@ func populateGuiData() *GuiData {
 		Commits:     31,
 		Release:     1,
 		Contributor: 1,
-		RowsInTable: []TableRow{},
+		RowsInTable: []guidata.TableRow{},
+		Now:         time.Now(),
 	}
-	guiData.RowsInTable = append(guiData.RowsInTable,
-		TableRow{"do_this.go", "Initial commit", "1 month ago", "file"})
-	guiData.RowsInTable = append(guiData.RowsInTable,
-		TableRow{"do_that.go", "Initial commit", "1 month ago", "file"})
-	guiData.RowsInTable = append(guiData.RowsInTable,
-		TableRow{"index.go", "Initial commit", "1 month ago", "file"})
-	guiData.RowsInTable = append(guiData.RowsInTable,
-		TableRow{"resources", "Initial commit", "2 months ago", "folder-open"})
-	guiData.RowsInTable = append(guiData.RowsInTable,
-		TableRow{"docs", "Initial commit", "2 months ago", "folder-open"})
-	return guiData
+	data.RowsInTable = append(data.RowsInTable,
+		guidata.TableRow{File: "do_this.go", Comment: "Initial commit", Ago: "1 month ago", Icon: "file"})
+	data.RowsInTable = append(data.RowsInTable,
+		guidata.TableRow{File: "do_that.go", Comment: "Initial commit", Ago: "1 month ago", Icon: "file"})
+	data.RowsInTable = append(data.RowsInTable,
+		guidata.TableRow{File: "index.go", Comment: "Initial commit", Ago: "1 month ago", Icon: "file"})
+	data.RowsInTable = append(data.RowsInTable,
+		guidata.TableRow{File: "resources", Comment: "Initial commit", Ago: "2 months ago", Icon: "folder-open"})
+	data.RowsInTable = append(data.RowsInTable,
+		guidata.TableRow{File: "docs", Comment: "Initial commit", Ago: "2 months ago", Icon: "folder-open"})
+	return data
 }
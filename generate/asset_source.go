@@ -0,0 +1,34 @@
+package generate
+
+import (
+	"embed"
+	"net/http"
+)
+
+// AssetSource is anything that can serve the GUI's compiled-in assets and
+// templates as a file tree. It exists so the rest of the app can be
+// written against one abstraction while choosing, at startup, between the
+// assets baked in at generation time, an embed.FS baked in by the Go
+// toolchain, or a plain directory on disk for development.
+type AssetSource interface {
+	http.FileSystem
+}
+
+// NewGeneratedAssetSource wraps the map of path -> contents produced by the
+// asset compiler (see generated_files.go) as an AssetSource.
+func NewGeneratedAssetSource(files map[string]string) AssetSource {
+	return newCompiledFileSystem(files)
+}
+
+// NewEmbedAssetSource wraps a Go 1.16+ `//go:embed` file system as an
+// AssetSource.
+func NewEmbedAssetSource(fsys embed.FS) AssetSource {
+	return http.FS(fsys)
+}
+
+// NewDevAssetSource wraps a plain directory on disk as an AssetSource, so
+// assets and templates can be edited and reloaded without recompiling the
+// binary.
+func NewDevAssetSource(dir string) AssetSource {
+	return http.Dir(dir)
+}
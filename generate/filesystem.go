@@ -0,0 +1,149 @@
+// Package generate provides the machinery that compiles the contents of the
+// "files" directory (html, css, javascript and template assets) into the
+// executable, so that the application has no run-time dependency on files
+// living on disk.
+package generate
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// compiledFileSystem is an in-memory implementation of http.FileSystem, whose
+// contents are populated from the generated compiledFiles map (see
+// generated_files.go). It supports both Open (for http.FileServer) and
+// Readdir (so code such as generate.TemplateEngine can walk it like a real
+// directory tree).
+type compiledFileSystem struct {
+	files map[string]*compiledFile
+}
+
+// compiledFile is a single file (or directory) baked into the executable.
+type compiledFile struct {
+	name    string
+	isDir   bool
+	modTime time.Time
+	content []byte
+}
+
+// newCompiledFileSystem builds a compiledFileSystem from a flat map of
+// path -> file contents, synthesising the intermediate directory entries
+// required to make Readdir work.
+func newCompiledFileSystem(files map[string]string) *compiledFileSystem {
+	fs := &compiledFileSystem{files: map[string]*compiledFile{}}
+	now := time.Now()
+	for name, contents := range files {
+		name = cleanPath(name)
+		fs.files[name] = &compiledFile{
+			name:    name,
+			modTime: now,
+			content: []byte(contents),
+		}
+		for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dir = cleanPath(dir)
+			if _, ok := fs.files[dir]; !ok {
+				fs.files[dir] = &compiledFile{name: dir, isDir: true, modTime: now}
+			}
+		}
+	}
+	if _, ok := fs.files[""]; !ok {
+		fs.files[""] = &compiledFile{name: "", isDir: true, modTime: now}
+	}
+	return fs
+}
+
+func cleanPath(name string) string {
+	return strings.Trim(path.Clean("/"+name), "/")
+}
+
+// Open implements http.FileSystem.
+func (fs *compiledFileSystem) Open(name string) (http.File, error) {
+	f, ok := fs.files[cleanPath(name)]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return &openFile{fs: fs, compiledFile: f}, nil
+}
+
+// children returns the direct children of dir, sorted by name - the
+// entries that openFile.Readdir reports for a directory.
+func (fs *compiledFileSystem) children(dir string) []*compiledFile {
+	dir = cleanPath(dir)
+	var out []*compiledFile
+	for name, f := range fs.files {
+		if name == dir {
+			continue
+		}
+		if path.Dir(name) == dir || (dir == "" && !strings.Contains(name, "/")) {
+			out = append(out, f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// openFile adapts a compiledFile to the http.File interface.
+type openFile struct {
+	fs *compiledFileSystem
+	*compiledFile
+	reader  *bytes.Reader
+	dirPos  int
+}
+
+func (f *openFile) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		f.reader = bytes.NewReader(f.content)
+	}
+	return f.reader.Read(p)
+}
+
+func (f *openFile) Seek(offset int64, whence int) (int64, error) {
+	if f.reader == nil {
+		f.reader = bytes.NewReader(f.content)
+	}
+	return f.reader.Seek(offset, whence)
+}
+
+func (f *openFile) Close() error { return nil }
+
+func (f *openFile) Stat() (os.FileInfo, error) { return fileInfo{f.compiledFile}, nil }
+
+func (f *openFile) Readdir(count int) ([]os.FileInfo, error) {
+	children := f.fs.children(f.name)
+	if f.dirPos >= len(children) && count > 0 {
+		return nil, os.ErrNotExist
+	}
+	if count <= 0 {
+		count = len(children) - f.dirPos
+	}
+	end := f.dirPos + count
+	if end > len(children) {
+		end = len(children)
+	}
+	infos := make([]os.FileInfo, 0, end-f.dirPos)
+	for _, c := range children[f.dirPos:end] {
+		infos = append(infos, fileInfo{c})
+	}
+	f.dirPos = end
+	return infos, nil
+}
+
+// fileInfo adapts a compiledFile to the os.FileInfo interface.
+type fileInfo struct{ *compiledFile }
+
+func (fi fileInfo) Name() string       { return path.Base(fi.name) }
+func (fi fileInfo) Size() int64        { return int64(len(fi.content)) }
+func (fi fileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+func (fi fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi fileInfo) IsDir() bool        { return fi.isDir }
+func (fi fileInfo) Sys() interface{}   { return nil }
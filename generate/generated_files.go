@@ -0,0 +1,137 @@
+// Code generated by the godesktopgui asset compiler from the contents of
+// the "files" directory. DO NOT EDIT.
+//
+// See newCompiledFileSystem in filesystem.go for how CompiledFileSystem is
+// built from this map.
+package generate
+
+var compiledFiles = map[string]string{
+	"files/app.css": `body { font-family: sans-serif; }`,
+	"files/app.js": `// Subscribes to the GUI's live data stream and patches the DOM in place,
+// so the page reflects server-side changes without a reload. Every message
+// after the first is a diff - only the fields that actually changed - so
+// this only touches the elements for fields present in the payload.
+(function () {
+  var statFields = ["Unwatch", "Star", "Fork", "Commits", "Release", "Contributor"];
+
+  function patch(data) {
+    statFields.forEach(function (field) {
+      if (!(field in data)) {
+        return;
+      }
+      var el = document.getElementById("stat-" + field.toLowerCase());
+      if (el) {
+        el.textContent = data[field];
+      }
+    });
+
+    var body = document.getElementById("file-table-body");
+    if (body && data.RowsInTable) {
+      while (body.firstChild) {
+        body.removeChild(body.firstChild);
+      }
+      data.RowsInTable.forEach(function (row) {
+        body.appendChild(buildRow(row));
+      });
+    }
+  }
+
+  // buildRow builds a table row from DOM APIs rather than string
+  // concatenation into innerHTML, so row data is never parsed as HTML -
+  // matching the auto-escaping the server's html/template render already
+  // gives the same fields on first load.
+  function buildRow(row) {
+    var tr = document.createElement("tr");
+
+    var iconCell = document.createElement("td");
+    var icon = document.createElement("i");
+    icon.className = "icon-" + row.Icon;
+    iconCell.appendChild(icon);
+    tr.appendChild(iconCell);
+
+    [row.File, row.Comment, row.Ago].forEach(function (text) {
+      var cell = document.createElement("td");
+      cell.textContent = text;
+      tr.appendChild(cell);
+    });
+
+    return tr;
+  }
+
+  if (window.EventSource) {
+    var source = new EventSource("/thegui/events");
+    source.addEventListener("message", function (event) {
+      patch(JSON.parse(event.data));
+    });
+  }
+
+  // Tell the server to shut down when the GUI's tab or window actually
+  // closes, so this behaves like a desktop app quitting rather than a
+  // server that keeps running in the background. pagehide fires more
+  // reliably than beforeunload across browsers (including on mobile and
+  // when navigating away), and sendBeacon is designed for exactly this:
+  // firing a request that is allowed to outlive the page.
+  window.addEventListener("pagehide", function () {
+    if (navigator.sendBeacon) {
+      navigator.sendBeacon("/quit");
+    } else {
+      var xhr = new XMLHttpRequest();
+      xhr.open("POST", "/quit", false);
+      xhr.send();
+    }
+  });
+})();
+`,
+	"files/templates/layouts/master.html": `{{define "layout"}}
+<!DOCTYPE html>
+<html>
+<head>
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="{{asset "app.css"}}">
+</head>
+<body>
+  {{template "header" .}}
+  {{block "content" .}}{{end}}
+  {{template "footer" .}}
+  <script src="{{asset "app.js"}}"></script>
+</body>
+</html>
+{{end}}
+`,
+	"files/templates/maingui.html": `{{define "content"}}
+<ul id="stats">
+  <li>Unwatch <span id="stat-unwatch">{{.Unwatch}}</span></li>
+  <li>Star <span id="stat-star">{{.Star}}</span></li>
+  <li>Fork <span id="stat-fork">{{.Fork}}</span></li>
+  <li>Commits <span id="stat-commits">{{.Commits}}</span></li>
+  <li>Release <span id="stat-release">{{.Release}}</span></li>
+  <li>Contributor <span id="stat-contributor">{{.Contributor}}</span></li>
+</ul>
+<table id="file-table">
+  <tbody id="file-table-body">
+  {{range .RowsInTable}}
+  <tr><td><i class="icon-{{.Icon}}"></i></td><td>{{.File}}</td><td>{{.Comment}}</td><td>{{.Ago}}</td></tr>
+  {{end}}
+  </tbody>
+</table>
+{{end}}
+`,
+	"files/templates/partials/footer.html": `{{define "footer"}}
+<footer>
+  <small>Rendered {{formatTime .Now}}</small>
+</footer>
+{{end}}
+`,
+	"files/templates/partials/header.html": `{{define "header"}}
+<header>
+  <h1>{{.Title}}</h1>
+</header>
+{{end}}
+`,
+}
+
+// CompiledFileSystem is the AssetSource that presents the contents of
+// compiledFiles (and therefore the original "files" directory) as if they
+// were ordinary files on disk, without any run-time dependency on disk
+// access.
+var CompiledFileSystem = NewGeneratedAssetSource(compiledFiles)
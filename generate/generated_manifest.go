@@ -0,0 +1,25 @@
+// Code generated by the godesktopgui asset compiler from the contents of
+// the "files" directory. DO NOT EDIT.
+//
+// See cmd/generate for the tool that produces this file, and
+// generated_files.go for the asset contents it describes.
+package generate
+
+// GeneratedManifest is the AssetManifest for the assets in compiledFiles,
+// keyed by logical name.
+var GeneratedManifest = NewAssetManifest(map[string]AssetMeta{
+	"app.css": {
+		URL:         "/files/app.511856738a30.css",
+		RealPath:    "files/app.css",
+		ContentType: "text/css; charset=utf-8",
+		ETag:        `"511856738a30d59d7e40379dcc85f8811108d3533680ea4e284abfc9b766b562"`,
+		GzipContent: mustDecodeBase64("H4sIAAAAAAAC/0vKT6lUqFZIy88r0U1LzM3MqbRSKE7MK9YtTi3KTLNWqAUA++BLGCEAAAA="),
+	},
+	"app.js": {
+		URL:         "/files/app.b863d01ccb11.js",
+		RealPath:    "files/app.js",
+		ContentType: "application/javascript; charset=utf-8",
+		ETag:        `"b863d01ccb110bdd107904fb5243819d88e3222d9faaf68ba79a9e32eeae13bf"`,
+		GzipContent: mustDecodeBase64("H4sIAAAAAAAC/41WUU/jOBB+51fM5WEv1bXp+yEedjm45QTLCljpJMSDm0xbi8Tu2g6lOvHf9xs7aVJA6F7axB7PfDP5vhnP53TbLnzp9II9BUthzfT3j4vfPdX6ialSQZEPjlVDylS0UaFciyXM/rq+Im1oU6uSp0fzOfl0fKNWTI6XNZfBk2f3xG7mdcVUrpVZ4fRWh7VtAymY1VZVBZ3BZkcNe4/D4kstA7vobqmdD6Q9rCu9XNKMrKl33RbXlYBR8FWGVtXYSEEq2HkrnsIaZ9MR2+7Bc80NG+BbWtf72Tj2WJOkUh67CO4oX7amDNoayif03xHRk3Ioigrn6dwJ3Wc/zFZKk00puw3Kyf+5dY/yf2qbRgcvjzcIqzynVRNQ9TZYlz0cH8HrPkosci6lT+FoFKwA3jOF7QFTRN9bEukl5b+lRckkuhl2CSUPrTPH3ftL9y8pcY1UKlu2UplixeEsFenL7qLKM8Ewy+iPVK0i2Eu7ZXeKdPLJ5HgUnOtxOK6LwM9B0pXankRA99HHwyGIl0ksQ8KysNXuIzRLXfMsqAV+xTTrEEj8ePTTpxiouLFbf2HuxHBAtV3jdDIsIr1OsVCNUcctx4194rj3xvZ1/V4He+c7Obt9E0JtNmyqLkSLX7iIhkOA7kkCvUh9QOneMj2IMmIlCAdp6WwTpfn5+4Unp8Bk0ZEyImNtVslDaU2p8EFUhKYNlK+NYff17upyKkIWV1H8EI9hqBOsdB6yUp7EiGbJUSNchduoGAU2z9iXatOvJPWjm6xDU88DN+gWQbqDqeBS1Wgs1S55WqHfJG161ey1DXSpASQpjlRyUK6ursKc4Ma8KREhcEedPAsuG7NMow6nXNcfnah6bvUHPjDWex7Crihr5f03SeaEMlmJ8gHc4gIvg6EgOGCCLHaOgnuzI+Z9Evfi7Rxsnka/0moAJL18XtmHd2goahx4KEmV/7cCFE1f6Vne+u1XaMuE9EDcqf3A8rgntGh2q00FyBgDJtza1pU8/qQ+riCW4S2NbPJsDr6sWj1nWfQ9zmRfqKqKxpfaAyu7POsGDPrvUI94dChIar7/3F5/KyLj036R2uiQyyDGO6newHUZon6N4YZ8DDoNm9FMhUwJ8yZlux9ZnSRr69lP0xSF6ha8VqKIWj+yzD72j8FuCPWln60OQSQ2lrdKbnoUMhMfmTfoAa0xYtyNtYUqH1fOtgZjV0b1WiYzNIZQjXWcvGAua7SUXXK9YNCIWyMaBGpnPeCBYojlKdemrNtKIqCcjV1Ib8VdITmKBTDqSa9URKy2ajeZxrsEZm31hZUoCukiP70yaDAykPkZtYnRtf8zOQLCeB7QfraMhhAzlGtBXWMQVVJ33CniraW/hUi/6Jj1lgt97gdk6HkgnOxQW1cMSAeevLcLPsqn6Wn4guHn+UBqz2vX0fjfq8uvIWxuUjb5XmGwKCwklGffr2/v5KaQfAKlgrcDO4mcj+cDnl8msvIL/1TH8dgJAAA="),
+	},
+})
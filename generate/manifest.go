@@ -0,0 +1,135 @@
+package generate
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AssetMeta describes one asset as published through an AssetManifest: the
+// cache-busting URL it is served at, the real path to open in an
+// AssetSource to read it, and the headers an AssetHandler should set when
+// serving it.
+type AssetMeta struct {
+	URL         string // cache-busting URL, e.g. "/files/app.abc123.css"
+	RealPath    string // path to open in the AssetSource, e.g. "files/app.css"
+	ContentType string
+	ETag        string
+	GzipContent []byte // precompressed content to serve instead, when the client accepts gzip; nil if none
+}
+
+// AssetManifest maps an asset's logical name (e.g. "app.css") to its
+// AssetMeta, and lets an AssetHandler look an asset back up by the
+// cache-busting URL it was published under.
+type AssetManifest struct {
+	byName map[string]AssetMeta
+	byURL  map[string]AssetMeta
+}
+
+// NewAssetManifest builds an AssetManifest from a map of logical name to
+// AssetMeta.
+func NewAssetManifest(entries map[string]AssetMeta) *AssetManifest {
+	m := &AssetManifest{byName: entries, byURL: map[string]AssetMeta{}}
+	for _, meta := range entries {
+		m.byURL[meta.URL] = meta
+	}
+	return m
+}
+
+// URL returns the cache-busting URL for the asset with the given logical
+// name, or "" if there is no such asset. Templates call this via the
+// "asset" FuncMap entry.
+func (m *AssetManifest) URL(name string) string {
+	return m.byName[name].URL
+}
+
+// RealPath returns the AssetSource path to open to read the asset with the
+// given logical name, or "" if there is no such asset.
+func (m *AssetManifest) RealPath(name string) string {
+	return m.byName[name].RealPath
+}
+
+// URLs returns every logical name in the manifest mapped to its
+// cache-busting URL.
+func (m *AssetManifest) URLs() map[string]string {
+	urls := make(map[string]string, len(m.byName))
+	for name, meta := range m.byName {
+		urls[name] = meta.URL
+	}
+	return urls
+}
+
+// NewAssetHandler returns an http.Handler that serves the assets in
+// manifest, read from source, with ETag and long-lived Cache-Control
+// headers (safe because the URL changes whenever the content does), and
+// Content-Encoding: gzip when the client accepts it.
+//
+// devMode must be true whenever source can change without the manifest
+// being regenerated (e.g. a NewDevAssetSource over a directory being
+// edited live) - otherwise meta.GzipContent's precompiled bytes would be
+// served forever regardless of what source actually contains now.
+// devMode re-reads and gzips source on every request instead, trading the
+// precompiled fast path for always reflecting source's current content; it
+// also sends Cache-Control: no-cache and no ETag, since the manifest's URL
+// and ETag do not change when such a source is edited.
+func NewAssetHandler(source AssetSource, manifest *AssetManifest, devMode bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		meta, ok := manifest.byURL[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", meta.ContentType)
+		if devMode {
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			w.Header().Set("ETag", meta.ETag)
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+			if r.Header.Get("If-None-Match") == meta.ETag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		if !devMode && meta.GzipContent != nil && acceptsGzip(r) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(meta.GzipContent)
+			return
+		}
+
+		f, err := source.Open(meta.RealPath)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+
+		if devMode && acceptsGzip(r) {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			io.Copy(gz, f)
+			return
+		}
+		io.Copy(w, f)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+}
+
+// mustDecodeBase64 decodes the base64 gzip payloads embedded in
+// generated_manifest.go. It panics on malformed input, which would mean the
+// asset compiler itself produced a broken manifest.
+func mustDecodeBase64(s string) []byte {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic("generate: malformed embedded gzip payload: " + err.Error())
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package generate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAssetHandlerDevModeServesLiveEdits guards against the precompiled
+// GzipContent fast path masking edits to a dev-mode AssetSource: a
+// gzip-accepting request must see a file's current content, not whatever
+// was true when the manifest was generated.
+func TestAssetHandlerDevModeServesLiveEdits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asset-handler-dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	assetPath := filepath.Join(dir, "app.css")
+	if err := ioutil.WriteFile(assetPath, []byte("body{color:red}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewDevAssetSource(dir)
+	manifest := NewAssetManifest(map[string]AssetMeta{
+		"app.css": {
+			URL:         "/files/app.abc123.css",
+			RealPath:    "app.css",
+			ContentType: "text/css; charset=utf-8",
+			ETag:        `"abc123"`,
+			GzipContent: mustGzip(t, "body{color:red}"),
+		},
+	})
+
+	handler := NewAssetHandler(source, manifest, true)
+
+	if err := ioutil.WriteFile(assetPath, []byte("body{color:blue}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/files/app.abc123.css", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	body := gunzip(t, rec.Body.Bytes())
+	if string(body) != "body{color:blue}" {
+		t.Errorf("body = %q, want %q (stale precompiled gzip was served instead of the live edit)", body, "body{color:blue}")
+	}
+}
+
+func mustGzip(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gunzip(t *testing.T, b []byte) []byte {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
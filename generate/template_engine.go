@@ -0,0 +1,213 @@
+package generate
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// TemplateEngine renders pages by combining a master layout (from a
+// "layouts/" subdirectory), a set of reusable partials (from a
+// "partials/" subdirectory, each a {{define}} block) and a page's own
+// template file, all read from a templatesRoot directory within an
+// http.FileSystem. This lets the GUI be composed the way a real web app
+// is - header/footer shared across pages - rather than each page being
+// one monolithic template.
+type TemplateEngine struct {
+	fs            http.FileSystem
+	templatesRoot string
+	funcMap       template.FuncMap
+	devMode       bool
+
+	// mu guards everything below. In devMode, load is re-run on every
+	// render, so concurrent requests (any real browser load fires more
+	// than one) would otherwise race on these maps.
+	mu            sync.RWMutex
+	defaultLayout string
+	layouts       map[string]*template.Template
+	pages         map[string]*template.Template
+	pageSource    map[string]string
+}
+
+// NewTemplateEngine builds a TemplateEngine by walking templatesRoot within
+// fs. If devMode is true, Render and RenderWithLayout re-read and re-parse
+// templates from fs on every call, instead of using the templates parsed at
+// construction time - handy for iterating on templates without recompiling
+// the binary (pair this with an http.Dir over the real "files" directory).
+func NewTemplateEngine(fs http.FileSystem, templatesRoot string, funcMap template.FuncMap, devMode bool) (*TemplateEngine, error) {
+	e := &TemplateEngine{
+		fs:            fs,
+		templatesRoot: templatesRoot,
+		funcMap:       funcMap,
+		devMode:       devMode,
+	}
+	if err := e.reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// reload acquires the write lock and (re)parses every layout, partial and
+// page found under templatesRoot.
+func (e *TemplateEngine) reload() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.load()
+}
+
+// load (re)parses every layout, partial and page found under templatesRoot.
+// Callers must hold e.mu for writing.
+func (e *TemplateEngine) load() error {
+	partials, err := e.readFiles(path.Join(e.templatesRoot, "partials"))
+	if err != nil {
+		return fmt.Errorf("reading partials: %w", err)
+	}
+	layoutFiles, err := e.readFiles(path.Join(e.templatesRoot, "layouts"))
+	if err != nil {
+		return fmt.Errorf("reading layouts: %w", err)
+	}
+
+	e.layouts = map[string]*template.Template{}
+	for name, contents := range layoutFiles {
+		t := template.New(name).Funcs(e.funcMap)
+		for _, p := range partials {
+			if t, err = t.Parse(p); err != nil {
+				return fmt.Errorf("parsing partial for layout %q: %w", name, err)
+			}
+		}
+		if t, err = t.Parse(contents); err != nil {
+			return fmt.Errorf("parsing layout %q: %w", name, err)
+		}
+		e.layouts[name] = t
+		if e.defaultLayout == "" || name == "master" {
+			e.defaultLayout = name
+		}
+	}
+
+	pageFiles, err := e.readFiles(e.templatesRoot)
+	if err != nil {
+		return fmt.Errorf("reading pages: %w", err)
+	}
+	e.pages = map[string]*template.Template{}
+	e.pageSource = pageFiles
+	for name, contents := range pageFiles {
+		t, err := e.cloneLayout(e.defaultLayout)
+		if err != nil {
+			return err
+		}
+		if t, err = t.Parse(contents); err != nil {
+			return fmt.Errorf("parsing page %q: %w", name, err)
+		}
+		e.pages[name] = t
+	}
+	return nil
+}
+
+// cloneLayout returns a fresh clone of the named master layout, so that
+// parsing a page's own template into it never pollutes the original.
+func (e *TemplateEngine) cloneLayout(layout string) (*template.Template, error) {
+	master, ok := e.layouts[layout]
+	if !ok {
+		return nil, fmt.Errorf("no such layout %q", layout)
+	}
+	return master.Clone()
+}
+
+// readFiles returns the contents of the immediate (non-recursive) files in
+// dir, keyed by basename without extension.
+func (e *TemplateEngine) readFiles(dir string) (map[string]string, error) {
+	f, err := e.fs.Open(dir)
+	if err != nil {
+		// A missing directory reads as empty rather than as an error. A
+		// templatesRoot without partials/ is valid; without layouts/,
+		// load fails for any page, as there is no layout to clone.
+		return map[string]string{}, nil
+	}
+	defer f.Close()
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		return map[string]string{}, nil
+	}
+	out := map[string]string{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(info.Name(), path.Ext(info.Name()))
+		contents, err := readFile(e.fs, path.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out[name] = contents
+	}
+	return out, nil
+}
+
+func readFile(fs http.FileSystem, name string) (string, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	buf := make([]byte, 0, 4096)
+	chunk := make([]byte, 4096)
+	for {
+		n, err := f.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return string(buf), nil
+}
+
+// Render writes the named page, combined with the engine's default layout
+// (the layout named "master", or the only layout present), to w.
+func (e *TemplateEngine) Render(w io.Writer, name string, data interface{}) error {
+	return e.RenderWithLayout(w, "", name, data)
+}
+
+// RenderWithLayout writes the named page, combined with the named layout,
+// to w. It is the escape hatch for pages that need a layout other than the
+// engine's default, e.g. a "plain" layout with no header/footer chrome.
+// Passing "" for layout selects the engine's default.
+func (e *TemplateEngine) RenderWithLayout(w io.Writer, layout, name string, data interface{}) error {
+	if e.devMode {
+		if err := e.reload(); err != nil {
+			return err
+		}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if layout == "" {
+		layout = e.defaultLayout
+	}
+
+	var t *template.Template
+	if layout == e.defaultLayout {
+		t = e.pages[name]
+	}
+	if t == nil {
+		source, ok := e.pageSource[name]
+		if !ok {
+			return fmt.Errorf("no such page %q", name)
+		}
+		cloned, err := e.cloneLayout(layout)
+		if err != nil {
+			return err
+		}
+		if t, err = cloned.Parse(source); err != nil {
+			return err
+		}
+	}
+	return t.ExecuteTemplate(w, "layout", data)
+}
@@ -0,0 +1,104 @@
+package generate
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func writeFixtureTemplates(t *testing.T, root string) {
+	t.Helper()
+	dirs := []string{"layouts", "partials"}
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"layouts/master.html": `{{define "layout"}}L[{{template "greeting" .}}{{block "content" .}}{{end}}]{{end}}`,
+		"partials/greet.html": `{{define "greeting"}}hi-{{end}}`,
+		"home.html":           `{{define "content"}}home:{{.}}{{end}}`,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTemplateEngineLayoutResolution(t *testing.T) {
+	dir, err := ioutil.TempDir("", "template-engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	templatesRoot := filepath.Join(dir, "templates")
+	if err := os.MkdirAll(templatesRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFixtureTemplates(t, templatesRoot)
+
+	engine, err := NewTemplateEngine(NewDevAssetSource(dir), "templates", template.FuncMap{}, false)
+	if err != nil {
+		t.Fatalf("NewTemplateEngine: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "home", "world"); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := "L[hi-home:world]"
+	if got := buf.String(); got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+// TestTemplateEngineDevModeConcurrentRender guards against the data race
+// that devMode's unsynchronized reload used to produce: run with
+// `go test -race` and concurrent renders must neither race nor error.
+func TestTemplateEngineDevModeConcurrentRender(t *testing.T) {
+	dir, err := ioutil.TempDir("", "template-engine-dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	templatesRoot := filepath.Join(dir, "templates")
+	if err := os.MkdirAll(templatesRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFixtureTemplates(t, templatesRoot)
+
+	engine, err := NewTemplateEngine(NewDevAssetSource(dir), "templates", template.FuncMap{}, true)
+	if err != nil {
+		t.Fatalf("NewTemplateEngine: %v", err)
+	}
+
+	const goroutines = 20
+	const iterations = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*iterations)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			for j := 0; j < iterations; j++ {
+				buf.Reset()
+				if err := engine.Render(&buf, "home", "world"); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Render: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package guidata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ServeSSE streams the store's value to the client as a Server-Sent Events
+// feed: the current value immediately as a full snapshot, then a fresh
+// "message" event for each subsequent store Update its subscription
+// receives (see Subscribe for when one is not delivered), carrying only the
+// fields that changed since the last event this connection received. It
+// runs until the request's context is cancelled (e.g. the browser
+// navigates away).
+func (s *Store) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch, cancel := s.Subscribe()
+	defer cancel()
+
+	last := s.Current()
+	if err := writeEvent(w, last); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-ch:
+			diff := diffGuiData(last, data)
+			last = data
+			if err := writeEvent(w, diff); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: message\ndata: %s\n\n", payload)
+	return err
+}
@@ -0,0 +1,140 @@
+// Package guidata holds the GUI's live data model, and the plumbing that
+// turns changes to it into a stream of events a browser can subscribe to.
+// It replaces a one-shot, hard-coded render with a genuine reactive GUI
+// backed by the Go process.
+package guidata
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// GuiData holds the GUI state data that is combined with the template to
+// render the GUI HTML, and that is pushed to subscribers whenever it
+// changes. Note it is a hierarchical structure - having a slice of
+// TableRow - which the directives in the templating system is clever
+// enough to iterate over.
+type GuiData struct {
+	Title       string
+	Unwatch     int
+	Star        int
+	Fork        int
+	Commits     int
+	Branch      int
+	Release     int
+	Contributor int
+	RowsInTable []TableRow
+	Now         time.Time
+}
+
+// TableRow is a slave model to describe a single row in an HTML table.
+type TableRow struct {
+	File    string
+	Comment string
+	Ago     string
+	Icon    string
+}
+
+// Store holds the current GuiData and notifies subscribers whenever it is
+// changed via Update. It is the single source of truth shared by the
+// template render, the SSE stream and the WebSocket action handler.
+type Store struct {
+	mu      sync.RWMutex
+	current GuiData
+
+	subMu       sync.Mutex
+	subscribers map[chan GuiData]bool
+}
+
+// NewStore creates a Store whose initial value is current.
+func NewStore(current GuiData) *Store {
+	return &Store{
+		current:     current,
+		subscribers: map[chan GuiData]bool{},
+	}
+}
+
+// Current returns the store's present value.
+func (s *Store) Current() GuiData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Update applies mutate to the store's value under lock, then notifies
+// every subscriber of the new value.
+func (s *Store) Update(mutate func(*GuiData)) GuiData {
+	s.mu.Lock()
+	mutate(&s.current)
+	updated := s.current
+	s.mu.Unlock()
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- updated:
+		default:
+			// Subscriber isn't keeping up; drop the update rather than
+			// block the writer that called Update.
+		}
+	}
+	return updated
+}
+
+// diffGuiData returns the fields of next that differ from prev, keyed by
+// their GuiData field name, so that subscribers who already have prev only
+// need to be sent what actually changed.
+func diffGuiData(prev, next GuiData) map[string]interface{} {
+	diff := map[string]interface{}{}
+	if prev.Title != next.Title {
+		diff["Title"] = next.Title
+	}
+	if prev.Unwatch != next.Unwatch {
+		diff["Unwatch"] = next.Unwatch
+	}
+	if prev.Star != next.Star {
+		diff["Star"] = next.Star
+	}
+	if prev.Fork != next.Fork {
+		diff["Fork"] = next.Fork
+	}
+	if prev.Commits != next.Commits {
+		diff["Commits"] = next.Commits
+	}
+	if prev.Branch != next.Branch {
+		diff["Branch"] = next.Branch
+	}
+	if prev.Release != next.Release {
+		diff["Release"] = next.Release
+	}
+	if prev.Contributor != next.Contributor {
+		diff["Contributor"] = next.Contributor
+	}
+	if !reflect.DeepEqual(prev.RowsInTable, next.RowsInTable) {
+		diff["RowsInTable"] = next.RowsInTable
+	}
+	if !prev.Now.Equal(next.Now) {
+		diff["Now"] = next.Now
+	}
+	return diff
+}
+
+// Subscribe registers a new subscriber and returns the channel it will
+// receive subsequent Updates on, plus a function to unregister it once the
+// subscriber is done (e.g. its HTTP request context is done). The channel
+// holds one value; an Update that finds it still full is not delivered to
+// this subscriber.
+func (s *Store) Subscribe() (ch chan GuiData, cancel func()) {
+	ch = make(chan GuiData, 1)
+	s.subMu.Lock()
+	s.subscribers[ch] = true
+	s.subMu.Unlock()
+	return ch, func() {
+		s.subMu.Lock()
+		delete(s.subscribers, ch)
+		s.subMu.Unlock()
+		close(ch)
+	}
+}
@@ -0,0 +1,64 @@
+package guidata
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffGuiData(t *testing.T) {
+	base := GuiData{
+		Title:       "demo",
+		Unwatch:     3,
+		Star:        0,
+		Fork:        2,
+		Commits:     31,
+		Release:     1,
+		Contributor: 1,
+		RowsInTable: []TableRow{{File: "a.go"}},
+		Now:         time.Unix(0, 0),
+	}
+
+	t.Run("no change yields empty diff", func(t *testing.T) {
+		if diff := diffGuiData(base, base); len(diff) != 0 {
+			t.Errorf("diffGuiData(base, base) = %v, want empty", diff)
+		}
+	})
+
+	t.Run("only changed scalar fields are included", func(t *testing.T) {
+		next := base
+		next.Star = 1
+		diff := diffGuiData(base, next)
+		want := map[string]interface{}{"Star": 1}
+		if !reflect.DeepEqual(diff, want) {
+			t.Errorf("diffGuiData = %v, want %v", diff, want)
+		}
+	})
+
+	t.Run("changed table is included wholesale", func(t *testing.T) {
+		next := base
+		next.RowsInTable = []TableRow{{File: "a.go"}, {File: "b.go"}}
+		diff := diffGuiData(base, next)
+		want := map[string]interface{}{"RowsInTable": next.RowsInTable}
+		if !reflect.DeepEqual(diff, want) {
+			t.Errorf("diffGuiData = %v, want %v", diff, want)
+		}
+	})
+}
+
+func TestStoreUpdateNotifiesSubscribers(t *testing.T) {
+	s := NewStore(GuiData{Star: 0})
+	ch, cancel := s.Subscribe()
+	defer cancel()
+
+	s.Update(func(d *GuiData) { d.Star = 1 })
+
+	select {
+	case got := <-ch:
+		if got.Star != 1 {
+			t.Errorf("got Star = %d, want 1", got.Star)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+}
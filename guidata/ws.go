@@ -0,0 +1,50 @@
+package guidata
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// Action is a client-side request to change the store's data, sent as a
+// JSON text message over the WebSocket connection ServeWS upgrades to.
+type Action struct {
+	Action string `json:"action"`
+}
+
+var upgrader = websocket.Upgrader{
+	// This is a single-user desktop-style app talking to its own local
+	// server, so there is no cross-origin request to guard against.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ServeWS upgrades the request to a WebSocket connection and applies
+// whatever Actions the client sends over it to the store, e.g.
+// {"action": "increment_stars"}. Unrecognised actions are logged and
+// ignored.
+func (s *Store) ServeWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("guidata: websocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	for {
+		var action Action
+		if err := conn.ReadJSON(&action); err != nil {
+			return
+		}
+		s.apply(action)
+	}
+}
+
+func (s *Store) apply(action Action) {
+	switch action.Action {
+	case "increment_stars":
+		s.Update(func(d *GuiData) { d.Star++ })
+	default:
+		log.Printf("guidata: ignoring unrecognised action %q", action.Action)
+	}
+}
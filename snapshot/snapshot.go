@@ -0,0 +1,172 @@
+// Package snapshot renders a fully static copy of the GUI to disk, by
+// driving the very same handlers used to serve it live (via
+// httptest.NewServer) and crawling the HTML they produce. The output is the
+// markup a browser sees against the running server, re-serialised by
+// html.Render and with its "/files/..." URLs rewritten to relative paths,
+// which makes it useful for offline documentation builds or CI artefacts of
+// the GUI's current state.
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/peterhoward42/godesktopgui/generate"
+)
+
+// Generate crawls the pages reachable from entryPaths (following internal
+// links it discovers), renders each one exactly as the live server would,
+// and writes the result under outDir, along with a copy of each manifest
+// asset under its cache-busting hashed name - the only "/files/..." paths
+// the live server actually serves (via generate.NewAssetHandler), and so
+// the only ones the rendered pages' rewritten links ever resolve to.
+func Generate(handler http.Handler, assets generate.AssetSource, manifest *generate.AssetManifest, entryPaths []string, outDir string) error {
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	if err := materialiseManifest(assets, manifest, outDir); err != nil {
+		return fmt.Errorf("materialising manifest assets: %w", err)
+	}
+
+	visited := map[string]bool{}
+	queue := append([]string{}, entryPaths...)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if visited[p] {
+			continue
+		}
+		visited[p] = true
+
+		links, err := renderPage(server.URL, p, outDir)
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", p, err)
+		}
+		for _, link := range links {
+			if !visited[link] {
+				queue = append(queue, link)
+			}
+		}
+	}
+	return nil
+}
+
+// renderPage fetches path p from the running server, rewrites its
+// "/files/..." URLs to be relative to where the page is written, writes the
+// result under outDir, and returns the internal page links it found.
+func renderPage(baseURL, p, outDir string) ([]string, error) {
+	resp, err := http.Get(baseURL + p)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", p, resp.Status)
+	}
+
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	toRoot := relativeToRoot(p)
+	var internalLinks []string
+	rewriteNode(doc, toRoot, &internalLinks)
+
+	outFile := outputPath(outDir, p)
+	if err := os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(outFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return internalLinks, html.Render(f, doc)
+}
+
+// rewriteNode walks doc, rewriting "/files/..." attributes to be relative
+// (prefixed with toRoot) and collecting same-origin page links that are not
+// under /files/ into *internalLinks.
+func rewriteNode(n *html.Node, toRoot string, internalLinks *[]string) {
+	if n.Type == html.ElementNode {
+		for i, attr := range n.Attr {
+			if attr.Key != "href" && attr.Key != "src" {
+				continue
+			}
+			if !strings.HasPrefix(attr.Val, "/") {
+				continue
+			}
+			if strings.HasPrefix(attr.Val, "/files/") {
+				n.Attr[i].Val = toRoot + strings.TrimPrefix(attr.Val, "/")
+				continue
+			}
+			if attr.Key == "href" {
+				*internalLinks = append(*internalLinks, attr.Val)
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		rewriteNode(c, toRoot, internalLinks)
+	}
+}
+
+// outputPath maps a server path to the file it should be written to, e.g.
+// "/thegui" -> "<outDir>/thegui/index.html" and "/" -> "<outDir>/index.html".
+func outputPath(outDir, p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return filepath.Join(outDir, "index.html")
+	}
+	return filepath.Join(outDir, p, "index.html")
+}
+
+// relativeToRoot returns the "../" prefix needed to reach outDir from the
+// file that outputPath(outDir, p) writes to. outputPath nests every
+// non-root page one directory deeper than its path suggests (to give it an
+// index.html of its own), so the required depth is one more than the
+// number of "/" separators in p.
+func relativeToRoot(p string) string {
+	trimmed := strings.Trim(p, "/")
+	if trimmed == "" {
+		return ""
+	}
+	depth := strings.Count(trimmed, "/") + 1
+	return strings.Repeat("../", depth)
+}
+
+// materialiseManifest writes each of manifest's assets to outDir under its
+// cache-busting URL only (e.g. outDir/files/app.abc123.css), since that is
+// the path the rendered pages actually reference.
+func materialiseManifest(assets generate.AssetSource, manifest *generate.AssetManifest, outDir string) error {
+	for name, url := range manifest.URLs() {
+		src, err := assets.Open(manifest.RealPath(name))
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(outDir, filepath.FromSlash(strings.TrimPrefix(url, "/")))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			src.Close()
+			return err
+		}
+		out, err := os.Create(dest)
+		if err != nil {
+			src.Close()
+			return err
+		}
+		_, err = io.Copy(out, src)
+		src.Close()
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
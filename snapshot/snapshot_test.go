@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/peterhoward42/godesktopgui/generate"
+)
+
+func TestRelativeToRoot(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/", ""},
+		{"/thegui", "../"},
+		{"/a/b", "../../"},
+	}
+	for _, c := range cases {
+		got := relativeToRoot(c.path)
+		if got != c.want {
+			t.Errorf("relativeToRoot(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
+
+// TestGenerateLinksResolve writes a snapshot of a handler whose single page
+// links to a manifest asset, then actually follows the written href from
+// the page it was rewritten in back to the file it points at, to catch the
+// kind of directory-depth mismatch a purely string-based assertion would
+// miss.
+func TestGenerateLinksResolve(t *testing.T) {
+	assetDir, err := ioutil.TempDir("", "snapshot-assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(assetDir)
+	if err := os.MkdirAll(filepath.Join(assetDir, "files"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(assetDir, "files", "app.css"), []byte("body{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	assetSource := generate.NewDevAssetSource(assetDir)
+	manifest := generate.NewAssetManifest(map[string]generate.AssetMeta{
+		"app.css": {
+			URL:         "/files/app.abc123.css",
+			RealPath:    "files/app.css",
+			ContentType: "text/css; charset=utf-8",
+			ETag:        `"abc123"`,
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/thegui", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><link rel="stylesheet" href="/files/app.abc123.css"></head><body>hi</body></html>`))
+	})
+	mux.Handle("/files/", generate.NewAssetHandler(assetSource, manifest, false))
+
+	outDir, err := ioutil.TempDir("", "snapshot-out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	if err := Generate(mux, assetSource, manifest, []string{"/thegui"}, outDir); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	pagePath := filepath.Join(outDir, "thegui", "index.html")
+	f, err := os.Open(pagePath)
+	if err != nil {
+		t.Fatalf("opening rendered page: %v", err)
+	}
+	defer f.Close()
+	doc, err := html.Parse(f)
+	if err != nil {
+		t.Fatalf("parsing rendered page: %v", err)
+	}
+
+	href := findHref(doc)
+	if href == "" {
+		t.Fatal("no stylesheet href found in rendered page")
+	}
+	if strings.HasPrefix(href, "/") {
+		t.Fatalf("href %q was not rewritten to be relative", href)
+	}
+
+	// This is the part a purely string-based assertion on href's value
+	// would miss: actually resolve it against the directory the page was
+	// written to, the way a browser would.
+	resolved := filepath.Join(filepath.Dir(pagePath), filepath.FromSlash(href))
+	if _, err := os.Stat(resolved); err != nil {
+		t.Fatalf("href %q (resolved to %s) does not exist: %v", href, resolved, err)
+	}
+
+	// The live server only ever serves assets under their cache-busting
+	// hashed URL (via generate.NewAssetHandler); a snapshot that also wrote
+	// the raw unhashed source path would publish a file no browser against
+	// the real server could ever reach, and - for templates - would leak
+	// server-side template markup into the published output.
+	if _, err := os.Stat(filepath.Join(outDir, "files", "app.css")); err == nil {
+		t.Error("snapshot wrote the raw unhashed app.css, which the live server never serves")
+	}
+}
+
+func findHref(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "link" {
+		for _, attr := range n.Attr {
+			if attr.Key == "href" {
+				return attr.Val
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if href := findHref(c); href != "" {
+			return href
+		}
+	}
+	return ""
+}